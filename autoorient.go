@@ -0,0 +1,236 @@
+package gift
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+)
+
+// autoOrientFilter applies the flip/rotate steps implied by a standard EXIF
+// orientation value so that the resulting image is right-side up.
+type autoOrientFilter struct {
+	steps []Filter
+}
+
+// AutoOrient creates a filter that transforms the source image according to
+// the given EXIF orientation value (1-8, as defined by the TIFF/EXIF
+// specification). Any other value is treated as 1 (identity), since that is
+// the default orientation assumed when no tag is present.
+//
+// image/jpeg and the other standard library decoders discard the EXIF
+// orientation tag, so images coming straight from a phone or camera often
+// need this filter applied before Resize or other filters that are
+// sensitive to the image's width and height.
+func AutoOrient(exifOrientation int) Filter {
+	return &autoOrientFilter{steps: orientationSteps(exifOrientation)}
+}
+
+// AutoOrientReader reads a JPEG or TIFF stream from r, extracts its EXIF
+// orientation tag if present, and returns a filter equivalent to calling
+// AutoOrient with that value. It returns an error only if r cannot be read
+// or a JPEG stream has a malformed segment length; a stream with no EXIF
+// data or no orientation tag yields the identity filter rather than an
+// error.
+func AutoOrientReader(r io.Reader) (Filter, error) {
+	orientation, err := readExifOrientation(r)
+	if err != nil {
+		return nil, err
+	}
+	return AutoOrient(orientation), nil
+}
+
+func orientationSteps(exifOrientation int) []Filter {
+	switch exifOrientation {
+	case 2:
+		return []Filter{FlipH()}
+	case 3:
+		return []Filter{Rotate180()}
+	case 4:
+		return []Filter{FlipV()}
+	case 5:
+		return []Filter{Transpose()}
+	case 6:
+		return []Filter{Rotate270()}
+	case 7:
+		return []Filter{Transverse()}
+	case 8:
+		return []Filter{Rotate90()}
+	default:
+		return nil
+	}
+}
+
+func (p *autoOrientFilter) Draw(dst draw.Image, src image.Image, options *Options) error {
+	if options == nil {
+		options = &defaultOptions
+	}
+	if len(p.steps) == 0 {
+		copyimage(dst, src, options)
+		return nil
+	}
+	g := NewWithOptions(*options, p.steps...)
+	return g.Draw(dst, src)
+}
+
+func (p *autoOrientFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	dstBounds = srcBounds
+	for _, f := range p.steps {
+		dstBounds = f.Bounds(dstBounds)
+	}
+	return
+}
+
+// exifOrientationTag is the EXIF tag ID for the "Orientation" field.
+const exifOrientationTag = 0x0112
+
+var errNoExifOrientation = errors.New("gift: no EXIF orientation tag found")
+
+// readExifOrientation scans a JPEG or bare TIFF stream for an EXIF
+// orientation tag, returning 1 (identity) if the stream has no EXIF data or
+// no orientation tag at all.
+func readExifOrientation(r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(4)
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+
+	var tiff io.Reader
+	switch {
+	case len(head) >= 2 && head[0] == 0xff && head[1] == 0xd8:
+		tiff, err = findJPEGExifSegment(br)
+		if err != nil {
+			if err == errNoExifOrientation {
+				return 1, nil
+			}
+			return 0, err
+		}
+	case len(head) >= 4 && (string(head) == "II*\x00" || string(head) == "MM\x00*"):
+		tiff = br
+	default:
+		return 1, nil
+	}
+
+	orientation, err := parseTiffOrientation(tiff)
+	if err != nil {
+		if err == errNoExifOrientation {
+			return 1, nil
+		}
+		return 0, err
+	}
+	return orientation, nil
+}
+
+// findJPEGExifSegment walks the marker segments of a JPEG stream looking for
+// an APP1 segment containing an "Exif\0\0" header, returning a reader
+// positioned at the start of the embedded TIFF structure.
+func findJPEGExifSegment(br *bufio.Reader) (io.Reader, error) {
+	var soi [2]byte
+	if _, err := io.ReadFull(br, soi[:]); err != nil {
+		return nil, err
+	}
+
+	for {
+		marker, err := readMarker(br)
+		if err != nil {
+			return nil, err
+		}
+		// SOS (start of scan) ends the metadata section; no point in
+		// scanning compressed image data for more markers. EOI (end of
+		// image) ends the stream and has no length field to read.
+		if marker == 0xda || marker == 0xd9 {
+			return nil, errNoExifOrientation
+		}
+
+		var length uint16
+		if err := binary.Read(br, binary.BigEndian, &length); err != nil {
+			return nil, err
+		}
+		if length < 2 {
+			return nil, fmt.Errorf("gift: invalid JPEG segment length %d", length)
+		}
+		payload := make([]byte, length-2)
+		if _, err := io.ReadFull(br, payload); err != nil {
+			return nil, err
+		}
+
+		if marker == 0xe1 && len(payload) > 6 && string(payload[:6]) == "Exif\x00\x00" {
+			return bytes.NewReader(payload[6:]), nil
+		}
+	}
+}
+
+func readMarker(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != 0xff {
+			continue
+		}
+		m, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if m == 0x00 || m == 0xff {
+			continue
+		}
+		return m, nil
+	}
+}
+
+// parseTiffOrientation parses the IFD0 of a TIFF structure (the body of an
+// EXIF segment) looking for the orientation tag.
+func parseTiffOrientation(r io.Reader) (int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) < 8 {
+		return 0, errNoExifOrientation
+	}
+
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, errNoExifOrientation
+	}
+
+	ifdOffset := order.Uint32(data[4:8])
+	if int(ifdOffset)+2 > len(data) {
+		return 0, errNoExifOrientation
+	}
+
+	numEntries := int(order.Uint16(data[ifdOffset : ifdOffset+2]))
+	entriesStart := int(ifdOffset) + 2
+	for i := 0; i < numEntries; i++ {
+		off := entriesStart + i*12
+		if off+12 > len(data) {
+			break
+		}
+		tag := order.Uint16(data[off : off+2])
+		if tag != exifOrientationTag {
+			continue
+		}
+		valueType := order.Uint16(data[off+2 : off+4])
+		if valueType != 3 { // SHORT
+			break
+		}
+		value := order.Uint16(data[off+8 : off+10])
+		if value < 1 || value > 8 {
+			return 0, errNoExifOrientation
+		}
+		return int(value), nil
+	}
+	return 0, errNoExifOrientation
+}
@@ -0,0 +1,72 @@
+package gift
+
+import (
+	"bytes"
+	"image"
+	"testing"
+)
+
+// tiffWithOrientation builds a minimal little-endian TIFF structure (as
+// found inside a JPEG's Exif APP1 segment) with a single IFD0 entry: the
+// orientation tag set to the given value.
+func tiffWithOrientation(orientation uint16) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString("II")            // byte order: little-endian
+	buf.Write([]byte{0x2a, 0x00})    // TIFF magic number (42)
+	buf.Write([]byte{0x08, 0, 0, 0}) // offset of IFD0
+
+	buf.Write([]byte{0x01, 0x00}) // 1 entry
+
+	buf.Write([]byte{0x12, 0x01}) // tag 0x0112 (Orientation)
+	buf.Write([]byte{0x03, 0x00}) // type 3 (SHORT)
+	buf.Write([]byte{0x01, 0, 0, 0})
+	buf.Write([]byte{byte(orientation), byte(orientation >> 8), 0, 0})
+
+	buf.Write([]byte{0, 0, 0, 0}) // next IFD offset (none)
+	return buf.Bytes()
+}
+
+func TestReadExifOrientationFromTiff(t *testing.T) {
+	for orientation := 1; orientation <= 8; orientation++ {
+		data := tiffWithOrientation(uint16(orientation))
+		got, err := readExifOrientation(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("orientation %d: readExifOrientation: %v", orientation, err)
+		}
+		if got != orientation {
+			t.Fatalf("orientation %d: readExifOrientation = %d", orientation, got)
+		}
+	}
+}
+
+func TestReadExifOrientationNoTiff(t *testing.T) {
+	got, err := readExifOrientation(bytes.NewReader([]byte{0xff, 0xd8, 0xff, 0xd9}))
+	if err != nil {
+		t.Fatalf("readExifOrientation: %v", err)
+	}
+	if got != 1 {
+		t.Fatalf("readExifOrientation = %d, want 1 (identity) for a stream with no Exif data", got)
+	}
+}
+
+func TestAutoOrientSwapsBoundsForRotations(t *testing.T) {
+	src := image.Rect(0, 0, 100, 200)
+
+	// Orientation 6 (Rotate270) and 8 (Rotate90) swap width/height.
+	for _, o := range []int{6, 8} {
+		f := AutoOrient(o)
+		b := f.Bounds(src)
+		if b.Dx() != 200 || b.Dy() != 100 {
+			t.Fatalf("orientation %d: Bounds = %v, want swapped 200x100", o, b)
+		}
+	}
+
+	// Orientation 1 (identity) and 3 (Rotate180) keep the same dimensions.
+	for _, o := range []int{1, 3} {
+		f := AutoOrient(o)
+		b := f.Bounds(src)
+		if b.Dx() != 100 || b.Dy() != 200 {
+			t.Fatalf("orientation %d: Bounds = %v, want unswapped 100x200", o, b)
+		}
+	}
+}
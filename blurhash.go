@@ -0,0 +1,199 @@
+package gift
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"strings"
+)
+
+// base83Chars is the alphabet used by the BlurHash encoding, in order of
+// digit value 0-82.
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// BlurHashFilter encodes the image it is drawn from into a compact BlurHash
+// string instead of transforming pixels. It is meant to be the last filter
+// in a GIFT list: it copies src to dst unchanged so a regular Draw call
+// still produces a usable image, and stashes the computed hash for
+// retrieval afterwards via String.
+type BlurHashFilter struct {
+	xComponents, yComponents int
+	hash                     string
+}
+
+// BlurHash creates a filter that computes a BlurHash placeholder string for
+// the image it is applied to, using xComponents*yComponents DCT components
+// (each in the range 1-9). The hash is retrieved with the filter's String
+// method after GIFT.Draw has run.
+func BlurHash(xComponents, yComponents int) *BlurHashFilter {
+	return &BlurHashFilter{xComponents: xComponents, yComponents: yComponents}
+}
+
+// String returns the BlurHash computed by the most recent Draw call, or an
+// empty string if the filter has not been drawn yet.
+func (p *BlurHashFilter) String() string {
+	return p.hash
+}
+
+func (p *BlurHashFilter) Draw(dst draw.Image, src image.Image, options *Options) error {
+	if options == nil {
+		options = &defaultOptions
+	}
+	p.hash = EncodeBlurHash(src, p.xComponents, p.yComponents)
+	copyimage(dst, src, options)
+	return nil
+}
+
+func (p *BlurHashFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	return srcBounds
+}
+
+// EncodeBlurHash computes the BlurHash string for src using xComponents
+// columns and yComponents rows of DCT basis functions. xComponents and
+// yComponents must each be between 1 and 9.
+func EncodeBlurHash(src image.Image, xComponents, yComponents int) string {
+	xComponents = clampComponents(xComponents)
+	yComponents = clampComponents(yComponents)
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= 0 || h <= 0 {
+		return ""
+	}
+
+	factors := make([][3]float64, xComponents*yComponents)
+	pixGetter := newPixelGetter(src)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			factors[j*xComponents+i] = dctBasisAverage(pixGetter, b, i, j)
+		}
+	}
+
+	dc := factors[0]
+	var maxACValue float64
+	for i := 1; i < len(factors); i++ {
+		for c := 0; c < 3; c++ {
+			maxACValue = math.Max(maxACValue, math.Abs(factors[i][c]))
+		}
+	}
+
+	var sb strings.Builder
+
+	sizeFlag := (xComponents - 1) + (yComponents-1)*9
+	sb.WriteString(encode83(sizeFlag, 1))
+
+	var quantisedMaxAC int
+	if maxACValue == 0 {
+		quantisedMaxAC = 0
+	} else {
+		quantisedMaxAC = int(math.Max(0, math.Min(82, math.Floor(maxACValue*166-0.5))))
+	}
+	sb.WriteString(encode83(quantisedMaxAC, 1))
+	actualMaxAC := (float64(quantisedMaxAC) + 1) / 166
+
+	sb.WriteString(encode83(encodeDC(dc), 4))
+
+	for i := 1; i < len(factors); i++ {
+		sb.WriteString(encode83(encodeAC(factors[i], actualMaxAC), 2))
+	}
+
+	return sb.String()
+}
+
+func clampComponents(n int) int {
+	if n < 1 {
+		return 1
+	}
+	if n > 9 {
+		return 9
+	}
+	return n
+}
+
+// dctBasisAverage computes the average of src, weighted by the 2D DCT basis
+// function for component (i, j), in linear light.
+func dctBasisAverage(pixGetter *pixelGetter, b image.Rectangle, i, j int) [3]float64 {
+	var r, g, bl float64
+	w, h := b.Dx(), b.Dy()
+	normalisation := 1.0
+	if i != 0 || j != 0 {
+		normalisation = 2.0
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			basis := math.Cos(math.Pi*float64(i)*float64(x)/float64(w)) *
+				math.Cos(math.Pi*float64(j)*float64(y)/float64(h))
+			px := pixGetter.getPixel(b.Min.X+x, b.Min.Y+y)
+			r += basis * srgbToLinear(px.r)
+			g += basis * srgbToLinear(px.g)
+			bl += basis * srgbToLinear(px.b)
+		}
+	}
+
+	scale := normalisation / float64(w*h)
+	return [3]float64{r * scale, g * scale, bl * scale}
+}
+
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSrgb(v float64) int {
+	v = math.Max(0, math.Min(1, v))
+	var s float64
+	if v <= 0.0031308 {
+		s = v * 12.92
+	} else {
+		s = 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	return int(math.Round(s * 255))
+}
+
+func encodeDC(c [3]float64) int {
+	r := linearToSrgb(c[0])
+	g := linearToSrgb(c[1])
+	b := linearToSrgb(c[2])
+	return r<<16 + g<<8 + b
+}
+
+func encodeAC(c [3]float64, maxValue float64) int {
+	quant := func(v float64) int {
+		q := int(math.Floor(signPow(v/maxValue, 0.5)*9 + 9.5))
+		if q < 0 {
+			q = 0
+		}
+		if q > 18 {
+			q = 18
+		}
+		return q
+	}
+	return quant(c[0])*19*19 + quant(c[1])*19 + quant(c[2])
+}
+
+func signPow(v, exp float64) float64 {
+	if v < 0 {
+		return -math.Pow(-v, exp)
+	}
+	return math.Pow(v, exp)
+}
+
+func encode83(value, length int) string {
+	buf := make([]byte, length)
+	for i := 1; i <= length; i++ {
+		digit := (value / pow83(length-i)) % 83
+		buf[i-1] = base83Chars[digit]
+	}
+	return string(buf)
+}
+
+func pow83(n int) int {
+	result := 1
+	for i := 0; i < n; i++ {
+		result *= 83
+	}
+	return result
+}
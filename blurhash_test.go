@@ -0,0 +1,62 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func decode83(s string) int {
+	value := 0
+	for _, c := range s {
+		value = value*83 + strings.IndexRune(base83Chars, c)
+	}
+	return value
+}
+
+// TestEncodeBlurHashFlatImage checks the parts of the BlurHash encoding
+// that are predictable independent of the DCT arithmetic: the header
+// (size flag, max-AC digit) for a flat-color image, which has no AC energy
+// at all.
+func TestEncodeBlurHashFlatImage(t *testing.T) {
+	const xComponents, yComponents = 4, 3
+
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	hash := EncodeBlurHash(img, xComponents, yComponents)
+
+	wantLen := 1 + 1 + 4 + 2*(xComponents*yComponents-1)
+	if len(hash) != wantLen {
+		t.Fatalf("len(hash) = %d, want %d", len(hash), wantLen)
+	}
+
+	sizeFlag := decode83(hash[0:1])
+	gotX, gotY := sizeFlag%9+1, sizeFlag/9+1
+	if gotX != xComponents || gotY != yComponents {
+		t.Fatalf("decoded components = %dx%d, want %dx%d", gotX, gotY, xComponents, yComponents)
+	}
+
+	if hash[1:2] != "0" {
+		t.Fatalf("max-AC digit = %q, want %q for a flat-color image with no AC energy", hash[1:2], "0")
+	}
+}
+
+// TestEncodeBlurHashClampsComponents checks that out-of-range component
+// counts are clamped to the valid 1-9 range rather than producing a
+// malformed size flag.
+func TestEncodeBlurHashClampsComponents(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	hash := EncodeBlurHash(img, 20, 0)
+
+	sizeFlag := decode83(hash[0:1])
+	gotX, gotY := sizeFlag%9+1, sizeFlag/9+1
+	if gotX != 9 || gotY != 1 {
+		t.Fatalf("decoded components = %dx%d, want 9x1 after clamping", gotX, gotY)
+	}
+}
@@ -0,0 +1,214 @@
+package detect
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Cascade is a parsed Haar cascade classifier: a sequence of boosted
+// rejection stages, each built from weak classifiers over rectangle
+// (Haar-like) features, evaluated against a fixed base window size.
+type Cascade struct {
+	Width, Height int
+	Stages        []Stage
+}
+
+// Stage is a single boosted stage of the cascade. A detection window is
+// rejected as soon as its accumulated weak-classifier sum falls below
+// Threshold for any stage.
+type Stage struct {
+	Threshold   float64
+	Classifiers []WeakClassifier
+}
+
+// WeakClassifier is a depth-1 decision stump: it evaluates Feature against
+// Threshold (scaled by the window's standard deviation) and contributes
+// either LeftValue or RightValue to its stage's sum.
+type WeakClassifier struct {
+	Feature    Feature
+	Threshold  float64
+	LeftValue  float64
+	RightValue float64
+}
+
+// Feature is a Haar-like feature: a small set of weighted rectangles, in
+// coordinates relative to the cascade's base window, whose weighted pixel
+// sum is the feature's response.
+type Feature struct {
+	Rects []FeatureRect
+	// Tilted features (rotated 45 degrees) are not evaluated; cascades that
+	// use them will simply never activate those particular weak
+	// classifiers, which in practice costs some accuracy but not
+	// correctness of the surrounding stages.
+	Tilted bool
+}
+
+// FeatureRect is one weighted rectangle of a Feature, in base-window
+// coordinates.
+type FeatureRect struct {
+	X, Y, W, H int
+	Weight     float64
+}
+
+// LoadOpenCVCascade parses a Haar cascade in the XML format produced by
+// OpenCV's opencv_traincascade tool (and shipped, e.g., as
+// haarcascade_frontalface_default.xml). Only stump-based (depth-1) weak
+// classifiers are supported, which covers the standard OpenCV Haar
+// cascades.
+func LoadOpenCVCascade(r io.Reader) (*Cascade, error) {
+	var storage xmlStorage
+	if err := xml.NewDecoder(r).Decode(&storage); err != nil {
+		return nil, fmt.Errorf("detect: decoding cascade xml: %w", err)
+	}
+
+	xc := storage.Cascade
+	if xc.Width == 0 || xc.Height == 0 {
+		return nil, fmt.Errorf("detect: cascade xml has no <width>/<height>")
+	}
+	if xc.Width != xc.Height {
+		// Detect slides a single square window size across the image and
+		// scales its features uniformly, which only produces a correctly
+		// proportioned window for a square base size. Non-square cascades
+		// (e.g. mouth, eyepair) would need independent x/y scale tracking
+		// through the scan, which isn't implemented here.
+		return nil, fmt.Errorf("detect: non-square cascades are not supported (base window is %dx%d)", xc.Width, xc.Height)
+	}
+
+	features := make([]Feature, len(xc.Features))
+	for i, xf := range xc.Features {
+		f := Feature{Tilted: xf.Tilted != 0}
+		for _, rectLine := range xf.Rects {
+			rect, err := parseFeatureRect(rectLine)
+			if err != nil {
+				return nil, fmt.Errorf("detect: feature %d: %w", i, err)
+			}
+			f.Rects = append(f.Rects, rect)
+		}
+		features[i] = f
+	}
+
+	c := &Cascade{Width: xc.Width, Height: xc.Height}
+	for si, xs := range xc.Stages {
+		stage := Stage{Threshold: xs.StageThreshold}
+		for wi, xw := range xs.WeakClassifiers {
+			wc, err := parseWeakClassifier(xw, features)
+			if err != nil {
+				return nil, fmt.Errorf("detect: stage %d classifier %d: %w", si, wi, err)
+			}
+			stage.Classifiers = append(stage.Classifiers, wc)
+		}
+		c.Stages = append(c.Stages, stage)
+	}
+	return c, nil
+}
+
+func parseFeatureRect(line string) (FeatureRect, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 5 {
+		return FeatureRect{}, fmt.Errorf("rect %q: want 5 fields, got %d", line, len(fields))
+	}
+	ints := make([]int, 4)
+	for i := 0; i < 4; i++ {
+		v, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return FeatureRect{}, err
+		}
+		ints[i] = v
+	}
+	weight, err := strconv.ParseFloat(fields[4], 64)
+	if err != nil {
+		return FeatureRect{}, err
+	}
+	return FeatureRect{X: ints[0], Y: ints[1], W: ints[2], H: ints[3], Weight: weight}, nil
+}
+
+// parseWeakClassifier converts the OpenCV "internalNodes"/"leafValues" pair
+// of a stump classifier, in the form
+//
+//	internalNodes: "<leftRef> <rightRef> <featureIdx> <threshold>"
+//	leafValues:    "<leaf0> <leaf1> ..."
+//
+// into a WeakClassifier. leftRef/rightRef are leaf references for a stump:
+// a value v <= 0 refers to leafValues[-v]. Only single-node (stump) trees
+// are supported, i.e. both refs must be leaf references rather than
+// pointing at a further internal node.
+func parseWeakClassifier(xw xmlWeakClassifier, features []Feature) (WeakClassifier, error) {
+	nodeFields := strings.Fields(xw.InternalNodes)
+	if len(nodeFields) != 4 {
+		return WeakClassifier{}, fmt.Errorf("unsupported internalNodes (not a stump): %q", xw.InternalNodes)
+	}
+	leftRef, err := strconv.Atoi(nodeFields[0])
+	if err != nil {
+		return WeakClassifier{}, err
+	}
+	rightRef, err := strconv.Atoi(nodeFields[1])
+	if err != nil {
+		return WeakClassifier{}, err
+	}
+	if leftRef > 0 || rightRef > 0 {
+		return WeakClassifier{}, fmt.Errorf("unsupported multi-node tree in internalNodes: %q", xw.InternalNodes)
+	}
+	featureIdx, err := strconv.Atoi(nodeFields[2])
+	if err != nil {
+		return WeakClassifier{}, err
+	}
+	if featureIdx < 0 || featureIdx >= len(features) {
+		return WeakClassifier{}, fmt.Errorf("feature index %d out of range", featureIdx)
+	}
+	threshold, err := strconv.ParseFloat(nodeFields[3], 64)
+	if err != nil {
+		return WeakClassifier{}, err
+	}
+
+	leafFields := strings.Fields(xw.LeafValues)
+	leftLeaf, rightLeaf := -leftRef, -rightRef
+	if leftLeaf < 0 || leftLeaf >= len(leafFields) || rightLeaf < 0 || rightLeaf >= len(leafFields) {
+		return WeakClassifier{}, fmt.Errorf("leaf reference out of range: %q against leafValues %q", xw.InternalNodes, xw.LeafValues)
+	}
+	leftValue, err := strconv.ParseFloat(leafFields[leftLeaf], 64)
+	if err != nil {
+		return WeakClassifier{}, err
+	}
+	rightValue, err := strconv.ParseFloat(leafFields[rightLeaf], 64)
+	if err != nil {
+		return WeakClassifier{}, err
+	}
+
+	return WeakClassifier{
+		Feature:    features[featureIdx],
+		Threshold:  threshold,
+		LeftValue:  leftValue,
+		RightValue: rightValue,
+	}, nil
+}
+
+// xmlStorage mirrors the top-level <opencv_storage><cascade>...</cascade>
+// wrapper that OpenCV wraps its cascades in.
+type xmlStorage struct {
+	Cascade xmlCascade `xml:"cascade"`
+}
+
+type xmlCascade struct {
+	Width    int          `xml:"width"`
+	Height   int          `xml:"height"`
+	Stages   []xmlStage   `xml:"stages>_"`
+	Features []xmlFeature `xml:"features>_"`
+}
+
+type xmlStage struct {
+	StageThreshold  float64             `xml:"stageThreshold"`
+	WeakClassifiers []xmlWeakClassifier `xml:"weakClassifiers>_"`
+}
+
+type xmlWeakClassifier struct {
+	InternalNodes string `xml:"internalNodes"`
+	LeafValues    string `xml:"leafValues"`
+}
+
+type xmlFeature struct {
+	Rects  []string `xml:"rects>_"`
+	Tilted int      `xml:"tilted"`
+}
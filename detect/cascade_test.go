@@ -0,0 +1,106 @@
+package detect
+
+import (
+	"strings"
+	"testing"
+)
+
+const tinySquareCascadeXML = `<?xml version="1.0"?>
+<opencv_storage>
+<cascade>
+  <width>2</width>
+  <height>2</height>
+  <stages>
+    <_>
+      <stageThreshold>-1.0</stageThreshold>
+      <weakClassifiers>
+        <_>
+          <internalNodes>
+            0 -1 0 0.0</internalNodes>
+          <leafValues>
+            -1.0 1.0</leafValues>
+        </_>
+      </weakClassifiers>
+    </_>
+  </stages>
+  <features>
+    <_>
+      <rects>
+        <_>
+          0 0 2 2 -1.</_>
+        <_>
+          0 0 2 1 2.</_>
+      </rects>
+      <tilted>0</tilted>
+    </_>
+  </features>
+</cascade>
+</opencv_storage>
+`
+
+const tinyNonSquareCascadeXML = `<?xml version="1.0"?>
+<opencv_storage>
+<cascade>
+  <width>4</width>
+  <height>2</height>
+  <stages>
+    <_>
+      <stageThreshold>-1.0</stageThreshold>
+      <weakClassifiers>
+        <_>
+          <internalNodes>
+            0 -1 0 0.0</internalNodes>
+          <leafValues>
+            -1.0 1.0</leafValues>
+        </_>
+      </weakClassifiers>
+    </_>
+  </stages>
+  <features>
+    <_>
+      <rects>
+        <_>
+          0 0 4 2 -1.</_>
+        <_>
+          0 0 4 1 2.</_>
+      </rects>
+      <tilted>0</tilted>
+    </_>
+  </features>
+</cascade>
+</opencv_storage>
+`
+
+func TestLoadOpenCVCascade(t *testing.T) {
+	c, err := LoadOpenCVCascade(strings.NewReader(tinySquareCascadeXML))
+	if err != nil {
+		t.Fatalf("LoadOpenCVCascade: %v", err)
+	}
+	if c.Width != 2 || c.Height != 2 {
+		t.Fatalf("cascade size = %dx%d, want 2x2", c.Width, c.Height)
+	}
+	if len(c.Stages) != 1 {
+		t.Fatalf("len(Stages) = %d, want 1", len(c.Stages))
+	}
+	stage := c.Stages[0]
+	if stage.Threshold != -1.0 {
+		t.Fatalf("stage.Threshold = %v, want -1.0", stage.Threshold)
+	}
+	if len(stage.Classifiers) != 1 {
+		t.Fatalf("len(Classifiers) = %d, want 1", len(stage.Classifiers))
+	}
+	wc := stage.Classifiers[0]
+	if wc.LeftValue != -1.0 || wc.RightValue != 1.0 {
+		t.Fatalf("leaf values = %v/%v, want -1.0/1.0", wc.LeftValue, wc.RightValue)
+	}
+	if len(wc.Feature.Rects) != 2 {
+		t.Fatalf("len(Feature.Rects) = %d, want 2", len(wc.Feature.Rects))
+	}
+}
+
+func TestLoadOpenCVCascadeRejectsNonSquare(t *testing.T) {
+	_, err := LoadOpenCVCascade(strings.NewReader(tinyNonSquareCascadeXML))
+	if err == nil {
+		t.Fatal("LoadOpenCVCascade: want error for non-square cascade, got nil")
+	}
+}
@@ -0,0 +1,253 @@
+package detect
+
+import (
+	"image"
+	"runtime"
+	"sync"
+)
+
+// DetectOptions are the parameters controlling a Detect call.
+type DetectOptions struct {
+	// MinSize and MaxSize bound the detection window size, in pixels of the
+	// source image. A zero MinSize defaults to the cascade's base size; a
+	// zero MaxSize defaults to the image size.
+	MinSize, MaxSize int
+
+	// ScaleFactor is how much the detection window grows between passes.
+	// Defaults to 1.25 if <= 1.
+	ScaleFactor float64
+
+	// MinNeighbors is how many overlapping raw detections are required
+	// before they are reported as a single grouped detection. Defaults to
+	// 3 if <= 0.
+	MinNeighbors int
+
+	// Workers is the number of goroutines used to scan window rows in
+	// parallel. Defaults to the number of CPUs if <= 0.
+	Workers int
+}
+
+func (o *DetectOptions) init() {
+	if o.ScaleFactor <= 1 {
+		o.ScaleFactor = 1.25
+	}
+	if o.MinNeighbors <= 0 {
+		o.MinNeighbors = 3
+	}
+	if o.Workers <= 0 {
+		o.Workers = runtime.NumCPU()
+	}
+}
+
+// Detect runs cascade against src, returning the bounding rectangles of the
+// regions that matched, in src's coordinate space. Overlapping raw
+// detections are merged per opts.MinNeighbors.
+func Detect(src image.Image, cascade *Cascade, opts DetectOptions) []image.Rectangle {
+	opts.init()
+
+	b := src.Bounds()
+	imgW, imgH := b.Dx(), b.Dy()
+
+	minSize := opts.MinSize
+	if minSize <= 0 {
+		minSize = min(cascade.Width, cascade.Height)
+	}
+	maxSize := opts.MaxSize
+	if maxSize <= 0 {
+		maxSize = min(imgW, imgH)
+	}
+
+	ii := newIntegralImage(src)
+
+	var mu sync.Mutex
+	var raw []image.Rectangle
+
+	for size := minSize; size <= maxSize; size = nextWindowSize(size, opts.ScaleFactor) {
+		scale := float64(size) / float64(min(cascade.Width, cascade.Height))
+		step := max(1, int(scale))
+
+		maxX := imgW - size
+		maxY := imgH - size
+		if maxX < 0 || maxY < 0 {
+			break
+		}
+
+		parallelizeRows(opts.Workers, 0, maxY+1, step, func(y int) {
+			for x := 0; x <= maxX; x += step {
+				if evaluateWindow(ii, cascade, b.Min.X+x, b.Min.Y+y, size, scale) {
+					mu.Lock()
+					raw = append(raw, image.Rect(b.Min.X+x, b.Min.Y+y, b.Min.X+x+size, b.Min.Y+y+size))
+					mu.Unlock()
+				}
+			}
+		})
+	}
+
+	return groupRectangles(raw, opts.MinNeighbors)
+}
+
+func nextWindowSize(size int, scaleFactor float64) int {
+	next := int(float64(size) * scaleFactor)
+	if next <= size {
+		next = size + 1
+	}
+	return next
+}
+
+// evaluateWindow runs the full cascade against the window of the given size
+// at (x, y), rejecting as soon as any stage's accumulated weak-classifier
+// sum falls below its threshold.
+func evaluateWindow(ii *integralImage, cascade *Cascade, x, y, size int, scale float64) bool {
+	_, stddev := ii.windowStats(x, y, size)
+
+	for _, stage := range cascade.Stages {
+		var stageSum float64
+		for _, wc := range stage.Classifiers {
+			if featureResponse(ii, wc.Feature, x, y, scale) < wc.Threshold*stddev {
+				stageSum += wc.LeftValue
+			} else {
+				stageSum += wc.RightValue
+			}
+		}
+		if stageSum < stage.Threshold {
+			return false
+		}
+	}
+	return true
+}
+
+// featureResponse computes a Haar feature's weighted rectangle sum, with
+// the feature's base-window-relative rectangles scaled and translated into
+// the current window. Tilted features are skipped (contribute 0), see
+// Feature.Tilted.
+//
+// The raw weighted sum grows with the scaled rectangles' area, i.e. with
+// scale^2, while the cascade's stage/weak thresholds are calibrated at the
+// cascade's native (unscaled) window size. The result is divided by
+// scale^2 to bring it back to that same per-pixel density before the
+// caller compares it against those thresholds, mirroring how windowStats
+// normalizes stddev by window area.
+func featureResponse(ii *integralImage, f Feature, winX, winY int, scale float64) float64 {
+	if f.Tilted {
+		return 0
+	}
+	var sum float64
+	for _, r := range f.Rects {
+		rx := winX + int(float64(r.X)*scale)
+		ry := winY + int(float64(r.Y)*scale)
+		rw := max(1, int(float64(r.W)*scale))
+		rh := max(1, int(float64(r.H)*scale))
+		sum += r.Weight * ii.rectSum(rx, ry, rw, rh)
+	}
+	return sum / (scale * scale)
+}
+
+// parallelizeRows calls fn once per row in [start, stop) stepping by step,
+// spreading the calls across up to workers goroutines.
+func parallelizeRows(workers, start, stop, step int, fn func(y int)) {
+	rows := make([]int, 0, (stop-start)/step+1)
+	for y := start; y < stop; y += step {
+		rows = append(rows, y)
+	}
+	if len(rows) == 0 {
+		return
+	}
+	if workers > len(rows) {
+		workers = len(rows)
+	}
+
+	var wg sync.WaitGroup
+	chunk := (len(rows) + workers - 1) / workers
+	for i := 0; i < workers; i++ {
+		lo := i * chunk
+		hi := min(lo+chunk, len(rows))
+		if lo >= hi {
+			continue
+		}
+		wg.Add(1)
+		go func(rs []int) {
+			defer wg.Done()
+			for _, y := range rs {
+				fn(y)
+			}
+		}(rows[lo:hi])
+	}
+	wg.Wait()
+}
+
+// groupRectangles merges overlapping raw detections, requiring at least
+// minNeighbors rectangles per group, and returns one averaged rectangle per
+// surviving group. This is the same rectangle-grouping approach OpenCV uses
+// to turn many overlapping raw detections into one result per object.
+func groupRectangles(rects []image.Rectangle, minNeighbors int) []image.Rectangle {
+	n := len(rects)
+	if n == 0 {
+		return nil
+	}
+
+	group := make([]int, n)
+	for i := range group {
+		group[i] = i
+	}
+	find := func(i int) int {
+		for group[i] != i {
+			group[i] = group[group[i]]
+			i = group[i]
+		}
+		return i
+	}
+	union := func(i, j int) {
+		ri, rj := find(i), find(j)
+		if ri != rj {
+			group[ri] = rj
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if rectanglesClose(rects[i], rects[j]) {
+				union(i, j)
+			}
+		}
+	}
+
+	sums := map[int]image.Rectangle{}
+	counts := map[int]int{}
+	for i, r := range rects {
+		root := find(i)
+		counts[root]++
+		if acc, ok := sums[root]; ok {
+			sums[root] = image.Rect(acc.Min.X+r.Min.X, acc.Min.Y+r.Min.Y, acc.Max.X+r.Max.X, acc.Max.Y+r.Max.Y)
+		} else {
+			sums[root] = r
+		}
+	}
+
+	var out []image.Rectangle
+	for root, count := range counts {
+		if count < minNeighbors {
+			continue
+		}
+		acc := sums[root]
+		out = append(out, image.Rect(acc.Min.X/count, acc.Min.Y/count, acc.Max.X/count, acc.Max.Y/count))
+	}
+	return out
+}
+
+// rectanglesClose reports whether two rectangles are close enough to be
+// considered the same detection: their centers each fall within the other
+// rectangle, roughly scaled to size.
+func rectanglesClose(a, b image.Rectangle) bool {
+	ac := a.Min.Add(a.Max).Div(2)
+	bc := b.Min.Add(b.Max).Div(2)
+	threshold := min(a.Dx(), a.Dy(), b.Dx(), b.Dy()) / 2
+	dx := ac.X - bc.X
+	if dx < 0 {
+		dx = -dx
+	}
+	dy := ac.Y - bc.Y
+	if dy < 0 {
+		dy = -dy
+	}
+	return dx <= threshold && dy <= threshold
+}
@@ -0,0 +1,93 @@
+package detect
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// flatGrayImage returns a uniform grayscale image of the given value, so
+// that any rectangle's average intensity is known exactly.
+func flatGrayImage(w, h int, value uint8) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetGray(x, y, color.Gray{Y: value})
+		}
+	}
+	return img
+}
+
+// TestFeatureResponseScaleInvariant is the regression test for the
+// Viola-Jones scale-normalization bug: a feature's response, once
+// normalized, must stay roughly constant as the detection window is scaled
+// up, since it is compared directly against thresholds calibrated at the
+// cascade's native window size.
+func TestFeatureResponseScaleInvariant(t *testing.T) {
+	const value = 100
+	img := flatGrayImage(64, 64, value)
+	ii := newIntegralImage(img)
+
+	feature := Feature{Rects: []FeatureRect{{X: 0, Y: 0, W: 4, H: 4, Weight: 1}}}
+
+	base := featureResponse(ii, feature, 0, 0, 1)
+	wantBase := float64(value) * 4 * 4
+	if math.Abs(base-wantBase) > 1 {
+		t.Fatalf("featureResponse at scale 1 = %v, want ~%v", base, wantBase)
+	}
+
+	for _, scale := range []float64{2, 3, 5} {
+		got := featureResponse(ii, feature, 0, 0, scale)
+		// Integer rounding of the scaled rectangle size introduces some
+		// slack, which grows a little with scale.
+		tolerance := wantBase * 0.15
+		if math.Abs(got-wantBase) > tolerance {
+			t.Fatalf("featureResponse at scale %v = %v, want ~%v (+/- %v)", scale, got, wantBase, tolerance)
+		}
+	}
+}
+
+// TestEvaluateWindowConsistentAcrossScales exercises the actual bug: a
+// feature whose rectangle spans the whole base window has a normalized
+// response that is scale-invariant (~value * width * height), so it should
+// land on the same side of its threshold regardless of window size. Without
+// the scale^2 normalization, the raw response grows with the window's area
+// and quickly blows past the threshold as size increases, flipping the
+// weak classifier's decision and therefore evaluateWindow's accept/reject
+// outcome purely as a function of scale.
+func TestEvaluateWindowConsistentAcrossScales(t *testing.T) {
+	const value = 100
+	img := flatGrayImage(256, 256, value)
+	ii := newIntegralImage(img)
+
+	// Normalized response for this feature over a flat "value" image is
+	// value*8*8 = 6400, constant across scales. The threshold sits just
+	// above that, so the weak classifier should consistently pick
+	// LeftValue (and the stage, and therefore the whole cascade, should
+	// consistently reject) at every window size.
+	cascade := &Cascade{
+		Width:  8,
+		Height: 8,
+		Stages: []Stage{
+			{
+				Threshold: 0,
+				Classifiers: []WeakClassifier{
+					{
+						Feature:    Feature{Rects: []FeatureRect{{X: 0, Y: 0, W: 8, H: 8, Weight: 1}}},
+						Threshold:  float64(value)*8*8 + 50,
+						LeftValue:  -1,
+						RightValue: 1,
+					},
+				},
+			},
+		},
+	}
+
+	for _, size := range []int{8, 16, 24, 40} {
+		scale := float64(size) / float64(cascade.Width)
+		if evaluateWindow(ii, cascade, 0, 0, size, scale) {
+			t.Fatalf("evaluateWindow accepted a window at size %d (scale %v); feature response isn't normalized for scale", size, scale)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Package detect implements Haar-cascade object detection (the algorithm
+// behind OpenCV's CascadeClassifier), for use as a pre/post-processing step
+// in a gift pipeline, e.g. cropping to a detected face before Resize.
+//
+// Cascades are loaded from the OpenCV XML format via LoadOpenCVCascade, and
+// applied to an image with Detect.
+package detect
@@ -0,0 +1,98 @@
+package detect
+
+import (
+	"image"
+	"math"
+)
+
+// integralImage holds the integral (summed-area) image and the
+// squared-integral image of a grayscale source, each padded with a leading
+// row and column of zeros so that rectSum/rectSumSq need no bounds checks
+// for rectangles starting at (0, 0).
+type integralImage struct {
+	w, h   int // width/height of the source image (not the padded tables)
+	sum    []float64
+	sumSq  []float64
+	stride int // w + 1
+}
+
+// newIntegralImage computes the integral image and squared-integral image
+// of src's luma channel.
+func newIntegralImage(src image.Image) *integralImage {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	stride := w + 1
+
+	ii := &integralImage{
+		w:      w,
+		h:      h,
+		stride: stride,
+		sum:    make([]float64, stride*(h+1)),
+		sumSq:  make([]float64, stride*(h+1)),
+	}
+
+	for y := 0; y < h; y++ {
+		var rowSum, rowSumSq float64
+		for x := 0; x < w; x++ {
+			gray := grayAt(src, b.Min.X+x, b.Min.Y+y)
+			rowSum += gray
+			rowSumSq += gray * gray
+			idx := (y+1)*stride + (x + 1)
+			ii.sum[idx] = ii.sum[idx-stride] + rowSum
+			ii.sumSq[idx] = ii.sumSq[idx-stride] + rowSumSq
+		}
+	}
+	return ii
+}
+
+// grayAt returns the luma of the pixel at (x, y), in the range [0, 255].
+func grayAt(src image.Image, x, y int) float64 {
+	r, g, b, _ := src.At(x, y).RGBA()
+	// Rec. 601 luma, operating on the 16-bit RGBA() values.
+	y16 := (19595*r + 38470*g + 7471*b + 1<<15) >> 16
+	return float64(y16) / 257
+}
+
+// rectSum returns the sum of pixel values within rectangle [x,y)-[x+w,y+h),
+// clamped to the image bounds.
+func (ii *integralImage) rectSum(x, y, w, h int) float64 {
+	x0, y0, x1, y1 := ii.clamp(x, y, w, h)
+	return ii.sum[y1*ii.stride+x1] - ii.sum[y1*ii.stride+x0] - ii.sum[y0*ii.stride+x1] + ii.sum[y0*ii.stride+x0]
+}
+
+// rectSumSq is the same as rectSum but over squared pixel values, used for
+// computing a window's variance.
+func (ii *integralImage) rectSumSq(x, y, w, h int) float64 {
+	x0, y0, x1, y1 := ii.clamp(x, y, w, h)
+	return ii.sumSq[y1*ii.stride+x1] - ii.sumSq[y1*ii.stride+x0] - ii.sumSq[y0*ii.stride+x1] + ii.sumSq[y0*ii.stride+x0]
+}
+
+func (ii *integralImage) clamp(x, y, w, h int) (x0, y0, x1, y1 int) {
+	x0 = max(0, min(x, ii.w))
+	y0 = max(0, min(y, ii.h))
+	x1 = max(0, min(x+w, ii.w))
+	y1 = max(0, min(y+h, ii.h))
+	return
+}
+
+// windowStats returns the mean and standard deviation of the window
+// [x,y)-[x+size,y+size), used to normalize feature responses against local
+// lighting/contrast.
+func (ii *integralImage) windowStats(x, y, size int) (mean, stddev float64) {
+	n := float64(size * size)
+	if n == 0 {
+		return 0, 1
+	}
+	sum := ii.rectSum(x, y, size, size)
+	sumSq := ii.rectSumSq(x, y, size, size)
+	mean = sum / n
+	variance := sumSq/n - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	stddev = math.Sqrt(variance)
+	if stddev < 1 {
+		stddev = 1
+	}
+	return
+}
@@ -35,6 +35,10 @@ type Filter interface {
 // Options is the parameters passed to image processing filters.
 type Options struct {
 	Workers int
+
+	// TileSize is the default tile size used by GIFT.DrawTiled when called
+	// with a tileSize of 0. It has no effect on Draw or DrawAt.
+	TileSize int
 }
 
 func (o *Options) init() {
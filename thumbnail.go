@@ -0,0 +1,92 @@
+package gift
+
+import (
+	"image"
+	"image/draw"
+)
+
+type thumbnailFilter struct {
+	maxW, maxH int
+	resampling Resampling
+}
+
+// Thumbnail creates a filter that resizes the source image to the largest
+// size that fits within maxW x maxH while preserving its aspect ratio, akin
+// to calling Resize with maxW/maxH computed by hand, without distorting or
+// cropping the image.
+func Thumbnail(maxW, maxH int, resampling Resampling) Filter {
+	return &thumbnailFilter{maxW: maxW, maxH: maxH, resampling: resampling}
+}
+
+func (p *thumbnailFilter) fitSize(srcBounds image.Rectangle) (w, h int) {
+	srcW, srcH := srcBounds.Dx(), srcBounds.Dy()
+	if srcW <= 0 || srcH <= 0 || p.maxW <= 0 || p.maxH <= 0 {
+		return p.maxW, p.maxH
+	}
+
+	w, h = p.maxW, p.maxH
+	if srcW*h > srcH*w {
+		h = max(1, w*srcH/srcW)
+	} else {
+		w = max(1, h*srcW/srcH)
+	}
+	return
+}
+
+func (p *thumbnailFilter) Draw(dst draw.Image, src image.Image, options *Options) error {
+	w, h := p.fitSize(src.Bounds())
+	return Resize(w, h, p.resampling).Draw(dst, src, options)
+}
+
+func (p *thumbnailFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	w, h := p.fitSize(srcBounds)
+	return Resize(w, h, p.resampling).Bounds(srcBounds)
+}
+
+type thumbnailFillFilter struct {
+	w, h       int
+	anchor     Anchor
+	resampling Resampling
+}
+
+// ThumbnailFill creates a filter that resizes the source image so it covers
+// a w x h box while preserving its aspect ratio, then crops it to w x h
+// using anchor, the classic "cover" thumbnail. Unlike Thumbnail, the result
+// always has the exact requested dimensions.
+func ThumbnailFill(w, h int, anchor Anchor, resampling Resampling) Filter {
+	return &thumbnailFillFilter{w: w, h: h, anchor: anchor, resampling: resampling}
+}
+
+func (p *thumbnailFillFilter) coverSize(srcBounds image.Rectangle) (w, h int) {
+	srcW, srcH := srcBounds.Dx(), srcBounds.Dy()
+	if srcW <= 0 || srcH <= 0 || p.w <= 0 || p.h <= 0 {
+		return p.w, p.h
+	}
+
+	w, h = p.w, p.h
+	if srcW*h < srcH*w {
+		h = max(1, w*srcH/srcW)
+	} else {
+		w = max(1, h*srcW/srcH)
+	}
+	return
+}
+
+func (p *thumbnailFillFilter) Draw(dst draw.Image, src image.Image, options *Options) error {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	coverW, coverH := p.coverSize(src.Bounds())
+	resized := createTempImage(image.Rect(0, 0, coverW, coverH))
+	if err := Resize(coverW, coverH, p.resampling).Draw(resized, src, options); err != nil {
+		return err
+	}
+
+	g := NewWithOptions(*options, CropToSize(p.w, p.h, p.anchor))
+	return g.Draw(dst, resized)
+}
+
+func (p *thumbnailFillFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	return image.Rect(0, 0, p.w, p.h)
+}
@@ -0,0 +1,61 @@
+package gift
+
+import (
+	"image"
+	"testing"
+)
+
+func TestThumbnailBoundsFitsBox(t *testing.T) {
+	cases := []struct {
+		name       string
+		srcW, srcH int
+		maxW, maxH int
+	}{
+		{"landscape", 400, 200, 100, 100},
+		{"portrait", 200, 400, 100, 100},
+		{"square", 300, 300, 150, 100},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := Thumbnail(c.maxW, c.maxH, LanczosResampling)
+			b := f.Bounds(image.Rect(0, 0, c.srcW, c.srcH))
+
+			if b.Dx() > c.maxW || b.Dy() > c.maxH {
+				t.Fatalf("Bounds = %v, want to fit within %dx%d", b, c.maxW, c.maxH)
+			}
+			if b.Dx() != c.maxW && b.Dy() != c.maxH {
+				t.Fatalf("Bounds = %v, want at least one dimension to exactly match the box %dx%d", b, c.maxW, c.maxH)
+			}
+
+			srcRatio := float64(c.srcW) / float64(c.srcH)
+			gotRatio := float64(b.Dx()) / float64(b.Dy())
+			if diff := srcRatio - gotRatio; diff > 0.05 || diff < -0.05 {
+				t.Fatalf("aspect ratio = %v, want ~%v (source %dx%d)", gotRatio, srcRatio, c.srcW, c.srcH)
+			}
+		})
+	}
+}
+
+func TestThumbnailFillBoundsExact(t *testing.T) {
+	cases := []struct {
+		name       string
+		srcW, srcH int
+	}{
+		{"landscape", 400, 200},
+		{"portrait", 200, 400},
+		{"square", 300, 300},
+	}
+	const w, h = 100, 150
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := ThumbnailFill(w, h, CenterAnchor, LanczosResampling)
+			b := f.Bounds(image.Rect(0, 0, c.srcW, c.srcH))
+
+			if b.Dx() != w || b.Dy() != h {
+				t.Fatalf("Bounds = %v, want exactly %dx%d regardless of source aspect", b, w, h)
+			}
+		})
+	}
+}
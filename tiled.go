@@ -0,0 +1,118 @@
+package gift
+
+import (
+	"image"
+	"image/draw"
+)
+
+// TiledFilter is an optional interface implemented by filters that need
+// access to source pixels beyond the rectangle they are drawing, such as
+// convolutions or resamplers. DrawTiled uses it to work out how much
+// surrounding context to read for each tile; filters that don't implement
+// it are assumed to need none.
+type TiledFilter interface {
+	// SupportPadding returns the number of extra source pixels required on
+	// each side of a tile in the x and y directions, so that the filter
+	// produces the same output for the tile as it would for the whole
+	// image.
+	SupportPadding() (padX, padY int)
+}
+
+// DrawTiled applies all the added filters to the src image and outputs the
+// result to dst, processing the image in tileSize x tileSize tiles instead
+// of materializing a full-resolution intermediate image between each
+// filter. This keeps memory usage roughly constant regardless of image
+// size, at the cost of re-running the filter chain once per tile.
+//
+// If tileSize is 0, the options' TileSize is used instead; if that is also
+// 0, DrawTiled falls back to Draw. DrawTiled also falls back to Draw if any
+// filter in the chain changes the image bounds (e.g. a rotation or resize),
+// since tiling requires a 1:1 mapping between source and destination tiles.
+func (g *GIFT) DrawTiled(dst draw.Image, src image.Image, tileSize int) error {
+	if tileSize <= 0 {
+		tileSize = g.options.TileSize
+	}
+	if tileSize <= 0 {
+		return g.Draw(dst, src)
+	}
+
+	srcb := src.Bounds()
+	for _, f := range g.filters {
+		if f.Bounds(srcb) != srcb {
+			return g.Draw(dst, src)
+		}
+	}
+
+	padX, padY := g.supportPadding()
+	pixSetter := newPixelSetter(dst)
+
+	for ty := srcb.Min.Y; ty < srcb.Max.Y; ty += tileSize {
+		for tx := srcb.Min.X; tx < srcb.Max.X; tx += tileSize {
+			tile := image.Rect(tx, ty, min(tx+tileSize, srcb.Max.X), min(ty+tileSize, srcb.Max.Y))
+			if err := g.drawTile(pixSetter, src, tile, padX, padY); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// supportPadding returns the total padding required to read a tile through
+// the whole filter chain, not just the single neediest filter: if filter A
+// needs padA context and is followed by filter B needing padB context, then
+// A's output must itself be correct padB pixels beyond the tile, which in
+// turn means A needs padA+padB pixels of real source data. Padding
+// therefore accumulates additively along the chain rather than taking the
+// max.
+func (g *GIFT) supportPadding() (padX, padY int) {
+	for _, f := range g.filters {
+		if tf, ok := f.(TiledFilter); ok {
+			fx, fy := tf.SupportPadding()
+			padX += fx
+			padY += fy
+		}
+	}
+	return
+}
+
+func (g *GIFT) drawTile(dst *pixelSetter, src image.Image, tile image.Rectangle, padX, padY int) error {
+	srcb := src.Bounds()
+	// The padded read rectangle is clipped to src's bounds, so a tile on the
+	// image border ends where the image does and its filters see the same
+	// edge as they would when drawing the whole image.
+	read := image.Rect(tile.Min.X-padX, tile.Min.Y-padY, tile.Max.X+padX, tile.Max.Y+padY).Intersect(srcb)
+
+	srcTile := extractPaddedTile(src, read, srcb)
+	dstTile := createTempImage(srcTile.Bounds())
+	if err := g.Draw(dstTile, srcTile); err != nil {
+		return err
+	}
+
+	pixGetter := newPixelGetter(dstTile)
+	for y := tile.Min.Y; y < tile.Max.Y; y++ {
+		for x := tile.Min.X; x < tile.Max.X; x++ {
+			dst.setPixel(x, y, pixGetter.getPixel(x-read.Min.X, y-read.Min.Y))
+		}
+	}
+	return nil
+}
+
+// extractPaddedTile copies the portion of src within r into a new image
+// positioned at the origin, clamping out-of-bounds reads to the nearest
+// edge pixel of srcb so filters get sensible context at the image border.
+// Like the package's other hot paths (blurhash.go, for example), it goes
+// through pixelGetter/pixelSetter rather than the generic image.Image
+// At/Set methods.
+func extractPaddedTile(src image.Image, r, srcb image.Rectangle) draw.Image {
+	out := createTempImage(image.Rect(0, 0, r.Dx(), r.Dy()))
+	pixGetter := newPixelGetter(src)
+	pixSetter := newPixelSetter(out)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		sy := max(srcb.Min.Y, min(y, srcb.Max.Y-1))
+		for x := r.Min.X; x < r.Max.X; x++ {
+			sx := max(srcb.Min.X, min(x, srcb.Max.X-1))
+			pixSetter.setPixel(x-r.Min.X, y-r.Min.Y, pixGetter.getPixel(sx, sy))
+		}
+	}
+	return out
+}